@@ -0,0 +1,194 @@
+// Package cdk wraps invocations of the `cdk` CLI used to deploy and destroy
+// the CloudFormation stacks that make up an AGC context.
+package cdk
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ProgressEventType discriminates the kind of update a ProgressEvent
+// carries, so that consumers can filter or branch on it without inspecting
+// every field.
+type ProgressEventType string
+
+const (
+	ProgressEventTypeStart    ProgressEventType = "start"
+	ProgressEventTypeProgress ProgressEventType = "progress"
+	ProgressEventTypeLog      ProgressEventType = "log"
+	ProgressEventTypeError    ProgressEventType = "error"
+	ProgressEventTypeComplete ProgressEventType = "complete"
+)
+
+// progressEventSchemaVersion is bumped whenever ProgressEvent's JSON wire
+// format changes incompatibly, so downstream consumers can negotiate or
+// reject events they don't understand.
+const progressEventSchemaVersion = 1
+
+// ProgressEvent is a single update emitted while a `cdk deploy` or
+// `cdk destroy` execution is in progress. Seq is a monotonically increasing
+// sequence number stamped by updateResultFromStream, letting consumers
+// detect gaps and replay or resume a stream reliably.
+type ProgressEvent struct {
+	ExecutionName string
+	Type          ProgressEventType
+	Seq           uint64
+	Timestamp     time.Time
+	CurrentStep   int
+	TotalSteps    int
+	Outputs       []string
+	Err           error
+}
+
+// ProgressStream carries ProgressEvents from a running cdk execution to
+// whatever is observing it, e.g. a console progress bar or a test harness.
+type ProgressStream chan ProgressEvent
+
+// Result is the terminal outcome of a cdk execution, accumulated from the
+// ProgressEvents seen on a ProgressStream. Its fields mirror ProgressEvent's
+// so that a Result can be converted back into one, e.g. to represent a
+// stream's final state as an event of its own.
+type Result struct {
+	ExecutionName string
+	Type          ProgressEventType
+	Seq           uint64
+	Timestamp     time.Time
+	CurrentStep   int
+	TotalSteps    int
+	Outputs       []string
+	Err           error
+}
+
+// progressEventWire is ProgressEvent's JSON wire representation. It exists
+// because ProgressEvent.Err is an error, which doesn't marshal on its own,
+// and because every event needs a schemaVersion so consumers can tell wire
+// format changes apart from data changes.
+type progressEventWire struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	ExecutionName string            `json:"executionName"`
+	Type          ProgressEventType `json:"type"`
+	Seq           uint64            `json:"seq"`
+	Timestamp     time.Time         `json:"timestamp"`
+	CurrentStep   int               `json:"currentStep"`
+	TotalSteps    int               `json:"totalSteps"`
+	Outputs       []string          `json:"outputs,omitempty"`
+	Err           string            `json:"err,omitempty"`
+}
+
+// MarshalJSON renders e in the stable, versioned wire format described by
+// progressEventWire.
+func (e ProgressEvent) MarshalJSON() ([]byte, error) {
+	wire := progressEventWire{
+		SchemaVersion: progressEventSchemaVersion,
+		ExecutionName: e.ExecutionName,
+		Type:          e.Type,
+		Seq:           e.Seq,
+		Timestamp:     e.Timestamp,
+		CurrentStep:   e.CurrentStep,
+		TotalSteps:    e.TotalSteps,
+		Outputs:       e.Outputs,
+	}
+	if e.Err != nil {
+		wire.Err = e.Err.Error()
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON populates e from the wire format written by MarshalJSON.
+func (e *ProgressEvent) UnmarshalJSON(data []byte) error {
+	var wire progressEventWire
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	*e = ProgressEvent{
+		ExecutionName: wire.ExecutionName,
+		Type:          wire.Type,
+		Seq:           wire.Seq,
+		Timestamp:     wire.Timestamp,
+		CurrentStep:   wire.CurrentStep,
+		TotalSteps:    wire.TotalSteps,
+		Outputs:       wire.Outputs,
+	}
+	if wire.Err != "" {
+		e.Err = errors.New(wire.Err)
+	}
+	return nil
+}
+
+// sendDataToReceiver forwards every event from input to output until input
+// is closed. An event carrying an error is collapsed into a terminal,
+// fully-stepped event so that a visible progress bar completes instead of
+// hanging partway through on failure.
+func sendDataToReceiver(input <-chan ProgressEvent, waitGroup *sync.WaitGroup, output ProgressStream) {
+	defer waitGroup.Done()
+
+	for event := range input {
+		if event.Err != nil {
+			output <- ProgressEvent{
+				ExecutionName: event.ExecutionName,
+				CurrentStep:   1,
+				TotalSteps:    1,
+			}
+			continue
+		}
+		output <- event
+	}
+}
+
+// updateResultFromStream consumes stream until it is closed. Each event is
+// stamped with the next monotonically increasing sequence number (and, if
+// it doesn't already have one, a timestamp) before result is overwritten
+// with its fields, so the accumulated Result always reflects the
+// most-recently-sequenced event. If pipeline is non-nil, every stamped event
+// is also forwarded to it so its sinks observe the same live progress a
+// caller's Result does. If broker is non-nil, every stamped event is also
+// published to it for the event's ExecutionName, so a ProgressServer
+// subscriber observes the same live progress, and the execution's
+// subscribers are closed out once stream ends.
+func updateResultFromStream(stream <-chan ProgressEvent, result *Result, waitGroup *sync.WaitGroup, pipeline *SinkPipeline, broker *ProgressBroker) {
+	defer waitGroup.Done()
+
+	var seq uint64
+	for event := range stream {
+		seq++
+		event.Seq = seq
+		if event.Timestamp.IsZero() {
+			event.Timestamp = time.Now()
+		}
+
+		result.ExecutionName = event.ExecutionName
+		result.Type = event.Type
+		result.Seq = event.Seq
+		result.Timestamp = event.Timestamp
+		result.CurrentStep = event.CurrentStep
+		result.TotalSteps = event.TotalSteps
+		result.Outputs = event.Outputs
+		result.Err = event.Err
+
+		if pipeline != nil {
+			pipeline.Handle(event)
+		}
+		if broker != nil {
+			broker.Publish(event)
+		}
+	}
+
+	if broker != nil && result.ExecutionName != "" {
+		broker.Close(result.ExecutionName)
+	}
+}
+
+// isForwardProgress reports whether newEvent was emitted after oldEvent in
+// its stream, using the Seq stamped by updateResultFromStream rather than
+// re-deriving order from CurrentStep/TotalSteps. Against an oldEvent with no
+// Seq (oldEvent.Seq == 0), any sequenced newEvent counts as progress; an
+// unsequenced newEvent (Seq 0) never does.
+func isForwardProgress(newEvent, oldEvent ProgressEvent) bool {
+	if oldEvent.Seq == 0 {
+		return newEvent.Seq > 0
+	}
+	return newEvent.Seq > oldEvent.Seq
+}
@@ -0,0 +1,141 @@
+package cdk
+
+import "sync"
+
+// subscriberBuffer is how many events a subscriber can fall behind by before
+// Publish starts coalescing non-critical events away for it.
+const subscriberBuffer = 16
+
+// ProgressBroker fans the ProgressEvents published for an execution out to
+// any number of independent subscribers, e.g. the CLI's own progress bar, an
+// SSE server, a log tailer, or a test harness, each on its own buffered
+// channel. Events that don't represent forward progress (per
+// isForwardProgress) are dropped per subscriber, and other progress events
+// are dropped for a subscriber whose buffer is full, while error, complete
+// and log events are always delivered. Such a must-deliver event blocks the
+// Publish call until a stalled subscriber makes room for it; meanwhile the
+// execution's subscribers not yet reached by that Publish don't receive the
+// event, and Close for that execution waits as well. b.mu, which guards the
+// subscriber registry, is never held during a channel send, so a stalled
+// subscriber doesn't block Publish, Subscribe or Close for other executions.
+type ProgressBroker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[int]*subscription
+	nextID      int
+}
+
+type subscription struct {
+	mu      sync.Mutex
+	events  chan ProgressEvent
+	last    ProgressEvent
+	hasLast bool
+}
+
+// NewProgressBroker returns a ProgressBroker with no subscribers.
+func NewProgressBroker() *ProgressBroker {
+	return &ProgressBroker{subscribers: make(map[string]map[int]*subscription)}
+}
+
+// Subscribe registers a new, independent subscriber for executionName and
+// returns the channel it will receive events on, along with a cancel func
+// that unsubscribes it. Callers must invoke cancel once they stop receiving,
+// typically in a defer, to release the subscription.
+func (b *ProgressBroker) Subscribe(executionName string) (<-chan ProgressEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+
+	sub := &subscription{events: make(chan ProgressEvent, subscriberBuffer)}
+	if b.subscribers[executionName] == nil {
+		b.subscribers[executionName] = make(map[int]*subscription)
+	}
+	b.subscribers[executionName][id] = sub
+
+	var cancelOnce sync.Once
+	cancel := func() {
+		cancelOnce.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if subs, ok := b.subscribers[executionName]; ok {
+				delete(subs, id)
+				if len(subs) == 0 {
+					delete(b.subscribers, executionName)
+				}
+			}
+		})
+	}
+	return sub.events, cancel
+}
+
+// Publish fans event out to every current subscriber of event.ExecutionName.
+// The subscriber registry is only read while b.mu is held; delivery happens
+// after b.mu is released, one subscriber at a time, so a subscriber stalled
+// on a must-deliver event blocks this Publish call, and with it delivery to
+// the subscribers after it, but never Publish for another execution.
+func (b *ProgressBroker) Publish(event ProgressEvent) {
+	b.mu.Lock()
+	subs := make([]*subscription, 0, len(b.subscribers[event.ExecutionName]))
+	for _, sub := range b.subscribers[event.ExecutionName] {
+		subs = append(subs, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		deliver(sub, event)
+	}
+}
+
+// Close closes every current subscriber channel for executionName, so a
+// subscriber ranging over it sees the stream end, and removes them. Publish
+// must not be called for executionName after Close. Each subscriber's
+// channel is closed under that subscriber's own lock, so Close can't race
+// with an in-flight deliver for the same subscriber.
+func (b *ProgressBroker) Close(executionName string) {
+	b.mu.Lock()
+	subs := b.subscribers[executionName]
+	delete(b.subscribers, executionName)
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.mu.Lock()
+		close(sub.events)
+		sub.mu.Unlock()
+	}
+}
+
+// deliver applies the drop-or-coalesce policy for a single subscriber: an
+// error, complete, or log event (e.g. a non-fatal sink failure, which has no
+// Seq of its own to compare against) is always delivered; anything else is
+// delivered only if it represents forward progress over the last event this
+// subscriber saw, and is dropped rather than blocking if the subscriber's
+// buffer is full. Everything here happens under sub's own lock, so a
+// must-deliver send that blocks (subscriber stalled, buffer full) blocks the
+// calling Publish and holds off any other deliver or Close for this
+// subscriber until the subscriber makes room.
+func deliver(sub *subscription, event ProgressEvent) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	mustDeliver := event.Err != nil ||
+		event.Type == ProgressEventTypeError ||
+		event.Type == ProgressEventTypeComplete ||
+		event.Type == ProgressEventTypeLog
+
+	if sub.hasLast && !mustDeliver && !isForwardProgress(event, sub.last) {
+		return
+	}
+	sub.last = event
+	sub.hasLast = true
+
+	if mustDeliver {
+		sub.events <- event
+		return
+	}
+
+	select {
+	case sub.events <- event:
+	default:
+	}
+}
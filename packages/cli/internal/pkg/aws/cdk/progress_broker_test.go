@@ -0,0 +1,144 @@
+package cdk
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ProgressBroker_PublishFansOutToEverySubscriber(t *testing.T) {
+	broker := NewProgressBroker()
+
+	eventsA, cancelA := broker.Subscribe("myExecution")
+	defer cancelA()
+	eventsB, cancelB := broker.Subscribe("myExecution")
+	defer cancelB()
+
+	sent := ProgressEvent{ExecutionName: "myExecution", Seq: 1, CurrentStep: 1, TotalSteps: 1}
+	broker.Publish(sent)
+
+	assert.Equal(t, sent, <-eventsA)
+	assert.Equal(t, sent, <-eventsB)
+}
+
+func Test_ProgressBroker_Publish_CoalescesForSlowSubscriber(t *testing.T) {
+	broker := NewProgressBroker()
+	events, cancel := broker.Subscribe("myExecution")
+	defer cancel()
+
+	// Fill the subscriber's buffer with genuine forward progress, without
+	// anyone draining it, then publish one more event that doesn't improve
+	// on the last one delivered (same Seq): it must be dropped rather than
+	// blocking Publish or growing the channel past its capacity.
+	for i := 0; i < subscriberBuffer; i++ {
+		broker.Publish(ProgressEvent{ExecutionName: "myExecution", Seq: uint64(i + 1)})
+	}
+	broker.Publish(ProgressEvent{ExecutionName: "myExecution", Seq: uint64(subscriberBuffer)})
+
+	assert.Len(t, events, subscriberBuffer)
+}
+
+func Test_ProgressBroker_Publish_AlwaysDeliversErrors(t *testing.T) {
+	broker := NewProgressBroker()
+	events, cancel := broker.Subscribe("myExecution")
+	defer cancel()
+
+	for i := 0; i < subscriberBuffer; i++ {
+		broker.Publish(ProgressEvent{ExecutionName: "myExecution", Seq: uint64(i + 1)})
+	}
+
+	errEvent := ProgressEvent{ExecutionName: "myExecution", Type: ProgressEventTypeError, Err: fmt.Errorf("boom")}
+	done := make(chan struct{})
+	go func() {
+		broker.Publish(errEvent)
+		close(done)
+	}()
+
+	// Drain the full buffer so the blocking delivery of errEvent can land.
+	for i := 0; i < subscriberBuffer; i++ {
+		<-events
+	}
+	<-done
+
+	assert.Equal(t, errEvent, <-events)
+}
+
+func Test_ProgressBroker_Close_ClosesSubscriberChannels(t *testing.T) {
+	broker := NewProgressBroker()
+	events, cancel := broker.Subscribe("myExecution")
+	defer cancel()
+
+	broker.Close("myExecution")
+
+	_, open := <-events
+	assert.False(t, open)
+}
+
+func Test_ProgressBroker_Publish_StalledSubscriberDoesNotBlockOtherExecutions(t *testing.T) {
+	broker := NewProgressBroker()
+
+	_, cancelStalled := broker.Subscribe("execA")
+	defer cancelStalled()
+	healthy, cancelHealthy := broker.Subscribe("execB")
+	defer cancelHealthy()
+
+	// Fill execA's subscriber buffer, then publish a must-deliver error
+	// event for it that nobody is around to drain: this is the stalled
+	// subscriber (e.g. a disconnected SSE client that never called cancel).
+	for i := 0; i < subscriberBuffer; i++ {
+		broker.Publish(ProgressEvent{ExecutionName: "execA", Seq: uint64(i + 1)})
+	}
+	go broker.Publish(ProgressEvent{ExecutionName: "execA", Type: ProgressEventTypeError, Err: fmt.Errorf("boom")})
+
+	// Give the stalled Publish a moment to actually block on execA's full,
+	// undrained channel before we exercise an unrelated execution.
+	time.Sleep(10 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		broker.Publish(ProgressEvent{ExecutionName: "execB", Seq: 1})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish for execB blocked behind a stalled subscriber on an unrelated execution")
+	}
+
+	require.Len(t, healthy, 1)
+	assert.Equal(t, ProgressEvent{ExecutionName: "execB", Seq: 1}, <-healthy)
+}
+
+func Test_ProgressBroker_ConcurrentSubscribeUnsubscribeUnderLoad(t *testing.T) {
+	broker := NewProgressBroker()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			execName := fmt.Sprintf("execution-%d", i%5)
+
+			events, cancel := broker.Subscribe(execName)
+			defer cancel()
+
+			for j := 0; j < 20; j++ {
+				broker.Publish(ProgressEvent{ExecutionName: execName, Seq: uint64(j + 1)})
+			}
+
+			for {
+				select {
+				case <-events:
+				default:
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,138 @@
+package cdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ProgressServer exposes running cdk executions as Server-Sent Events so
+// that external consumers (IDEs, dashboards, a future web UI) can observe a
+// `cdk deploy`/`destroy` in progress without tailing stdout. Each connection
+// gets its own subscription on the underlying ProgressBroker, so any number
+// of clients can watch the same execution independently.
+type ProgressServer struct {
+	broker *ProgressBroker
+
+	mu         sync.Mutex
+	executions map[string]struct{}
+}
+
+// NewProgressServer returns a ProgressServer that subscribes to broker for
+// events on the executions registered with RegisterExecution.
+func NewProgressServer(broker *ProgressBroker) *ProgressServer {
+	return &ProgressServer{broker: broker, executions: make(map[string]struct{})}
+}
+
+// RegisterExecution marks executionName as servable, so that
+// "/progress/{executionName}" starts accepting subscribers for it.
+func (s *ProgressServer) RegisterExecution(executionName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.executions[executionName] = struct{}{}
+}
+
+// ServeProgress starts an HTTP server on addr that streams each registered
+// execution's ProgressEvents as Server-Sent Events under
+// "/progress/{executionName}". It blocks until the server stops and returns
+// the error from http.ListenAndServe.
+func (s *ProgressServer) ServeProgress(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/progress/", s.handleProgress)
+	return http.ListenAndServe(addr, mux)
+}
+
+func (s *ProgressServer) handleProgress(w http.ResponseWriter, r *http.Request) {
+	executionName := strings.TrimPrefix(r.URL.Path, "/progress/")
+
+	s.mu.Lock()
+	_, ok := s.executions[executionName]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, cancel := s.broker.Subscribe(executionName)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	streamEvents(r.Context(), w, flusher, events)
+}
+
+// doneEventName is the SSE "event:" name for the terminal frame, kept
+// stable independent of ProgressEvent's internal Type vocabulary (which
+// uses "complete") since it's part of the SSE endpoint's documented wire
+// contract: "emit a final `done` event ... when the stream closes."
+const doneEventName = "done"
+
+// streamEvents writes each event from events to w as an SSE frame, flushing
+// after every write, then writes a final "done" frame carrying the Result
+// accumulated from those events once the channel is closed. If ctx is
+// cancelled first (e.g. the client disconnected), streamEvents returns
+// immediately without writing a terminal frame, leaving cancel's caller
+// (handleProgress, via its deferred cancel) to unsubscribe and free the
+// subscription rather than blocking on events forever.
+func streamEvents(ctx context.Context, w http.ResponseWriter, flusher http.Flusher, events <-chan ProgressEvent) {
+	var result Result
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				done := ProgressEvent(result)
+				done.Type = ProgressEventTypeComplete
+				writeSSEEvent(w, doneEventName, done)
+				flusher.Flush()
+				return
+			}
+
+			result.ExecutionName = event.ExecutionName
+			result.Type = event.Type
+			result.Seq = event.Seq
+			result.Timestamp = event.Timestamp
+			result.CurrentStep = event.CurrentStep
+			result.TotalSteps = event.TotalSteps
+			result.Outputs = event.Outputs
+			result.Err = event.Err
+
+			writeSSEEvent(w, eventTypeFor(event), event)
+			flusher.Flush()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// eventTypeFor resolves the SSE "event:" field for event, falling back to
+// inferring it from Err when a producer hasn't stamped Type.
+func eventTypeFor(event ProgressEvent) string {
+	if event.Type != "" {
+		return string(event.Type)
+	}
+	if event.Err != nil {
+		return string(ProgressEventTypeError)
+	}
+	return string(ProgressEventTypeProgress)
+}
+
+// writeSSEEvent writes event to w as a single SSE frame named eventName,
+// using event's own versioned JSON marshaling for the data payload.
+func writeSSEEvent(w http.ResponseWriter, eventName string, event ProgressEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		payload = []byte(fmt.Sprintf(`{"err":%q}`, err.Error()))
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventName, payload)
+}
@@ -0,0 +1,92 @@
+package cdk
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ProgressServer_handleProgress_UnknownExecution(t *testing.T) {
+	server := NewProgressServer(NewProgressBroker())
+
+	request := httptest.NewRequest("GET", "/progress/unknown", nil)
+	recorder := httptest.NewRecorder()
+
+	server.handleProgress(recorder, request)
+
+	assert.Equal(t, 404, recorder.Code)
+}
+
+func Test_streamEvents_WritesEventsAndDone(t *testing.T) {
+	events := make(chan ProgressEvent, 2)
+	events <- ProgressEvent{ExecutionName: "myExecution", Type: ProgressEventTypeProgress, Seq: 1, CurrentStep: 1, TotalSteps: 2}
+	events <- ProgressEvent{ExecutionName: "myExecution", Type: ProgressEventTypeError, Seq: 2, Err: errors.New("boom")}
+	close(events)
+
+	recorder := httptest.NewRecorder()
+	streamEvents(context.Background(), recorder, recorder, events)
+
+	body := recorder.Body.String()
+	assert.Contains(t, body, "event: progress")
+	assert.Contains(t, body, "event: error")
+	// The terminal SSE frame is always named "done" (chunk0-1's documented
+	// wire contract), regardless of the internal ProgressEventTypeComplete
+	// value carried in its JSON payload.
+	assert.Contains(t, body, "event: done")
+	assert.True(t, strings.Contains(body, `"err":"boom"`))
+	assert.True(t, strings.Contains(body, `"schemaVersion":1`))
+	assert.True(t, strings.Contains(body, `"type":"complete"`))
+}
+
+func Test_streamEvents_ReturnsWithoutDoneFrameWhenContextIsCancelled(t *testing.T) {
+	events := make(chan ProgressEvent)
+	defer close(events)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	recorder := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		streamEvents(ctx, recorder, recorder, events)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("streamEvents did not return after its context was cancelled")
+	}
+
+	assert.NotContains(t, recorder.Body.String(), "event: done")
+}
+
+func Test_ProgressServer_handleProgress_UnsubscribesWhenClientDisconnects(t *testing.T) {
+	broker := NewProgressBroker()
+	server := NewProgressServer(broker)
+	server.RegisterExecution("myExecution")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	request := httptest.NewRequest("GET", "/progress/myExecution", nil).WithContext(ctx)
+	recorder := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		server.handleProgress(recorder, request)
+		close(done)
+	}()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handleProgress did not return after the client's context was cancelled")
+	}
+}
@@ -0,0 +1,103 @@
+package cdk
+
+import (
+	"fmt"
+	"sync"
+)
+
+// sinkQueueSize bounds how many events a single sink can fall behind by
+// before SinkPipeline.Handle starts dropping events for it.
+const sinkQueueSize = 64
+
+// ProgressSink receives ProgressEvents as they flow through a SinkPipeline,
+// e.g. to ship them to CloudWatch Logs, a local file, or Prometheus metrics.
+// A SinkPipeline calls each sink's Handle from a goroutine of that sink's
+// own, draining the sink's bounded queue, so a Handle that blocks on I/O
+// delays only that sink; events arriving while its queue is full are
+// dropped for it.
+type ProgressSink interface {
+	Handle(event ProgressEvent) error
+	Close()
+}
+
+// SinkPipeline chains zero or more ProgressSinks onto a ProgressStream,
+// analogous to how a log-shipping pipeline wraps a base client. Each sink
+// runs behind its own bounded queue so a slow or failing sink can never
+// block the core progress loop or any other sink; a sink's error is
+// reported as a non-fatal log event rather than aborting the execution.
+type SinkPipeline struct {
+	executionName string
+	errEvents     *ProgressBroker
+
+	wg     sync.WaitGroup
+	queues []chan ProgressEvent
+	sinks  []ProgressSink
+}
+
+// NewSinkPipeline returns a SinkPipeline that fans every event given to
+// Handle out to sinks. Sink failures are published to errEvents (if
+// non-nil) as log events for executionName rather than propagated to the
+// caller.
+func NewSinkPipeline(executionName string, errEvents *ProgressBroker, sinks ...ProgressSink) *SinkPipeline {
+	pipeline := &SinkPipeline{
+		executionName: executionName,
+		errEvents:     errEvents,
+		sinks:         sinks,
+	}
+
+	for _, sink := range sinks {
+		queue := make(chan ProgressEvent, sinkQueueSize)
+		pipeline.queues = append(pipeline.queues, queue)
+
+		pipeline.wg.Add(1)
+		go pipeline.run(sink, queue)
+	}
+
+	return pipeline
+}
+
+func (p *SinkPipeline) run(sink ProgressSink, queue chan ProgressEvent) {
+	defer p.wg.Done()
+
+	for event := range queue {
+		if err := sink.Handle(event); err != nil {
+			p.reportSinkError(err)
+		}
+	}
+}
+
+func (p *SinkPipeline) reportSinkError(err error) {
+	if p.errEvents == nil {
+		return
+	}
+	p.errEvents.Publish(ProgressEvent{
+		ExecutionName: p.executionName,
+		Type:          ProgressEventTypeLog,
+		Outputs:       []string{fmt.Sprintf("progress sink error: %s", err)},
+	})
+}
+
+// Handle fans event out to every sink's queue without blocking: a sink that
+// has fallen behind has this event dropped for it alone rather than
+// stalling the core progress loop.
+func (p *SinkPipeline) Handle(event ProgressEvent) {
+	for _, queue := range p.queues {
+		select {
+		case queue <- event:
+		default:
+		}
+	}
+}
+
+// Close stops accepting new events for every sink, waits for their queues
+// to drain, and then closes each sink in turn.
+func (p *SinkPipeline) Close() {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+	p.wg.Wait()
+
+	for _, sink := range p.sinks {
+		sink.Close()
+	}
+}
@@ -0,0 +1,96 @@
+package cdk
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+)
+
+// CloudWatchLogEvent is a single log line handed to cloudWatchLogsAPI.
+type CloudWatchLogEvent struct {
+	Timestamp time.Time
+	Message   string
+}
+
+// cloudWatchLogsAPI is the subset of the CloudWatch Logs client that
+// CloudWatchLogsSink depends on, so tests can supply a fake instead of a
+// real AWS client.
+type cloudWatchLogsAPI interface {
+	PutLogEvents(logGroupName, logStreamName string, events []CloudWatchLogEvent) error
+}
+
+// CloudWatchLogsSink writes every ProgressEvent it handles as a single JSON
+// log line to the CloudWatch Logs stream it was created with.
+type CloudWatchLogsSink struct {
+	api           cloudWatchLogsAPI
+	logGroupName  string
+	logStreamName string
+}
+
+// NewCloudWatchLogsSink returns a CloudWatchLogsSink that writes events to
+// logStreamName within logGroupName, via api. The stream name is used as
+// given; it is not derived from the events' ExecutionName.
+func NewCloudWatchLogsSink(api cloudWatchLogsAPI, logGroupName, logStreamName string) *CloudWatchLogsSink {
+	return &CloudWatchLogsSink{api: api, logGroupName: logGroupName, logStreamName: logStreamName}
+}
+
+// Handle writes event as a single JSON log line to the sink's log stream.
+func (s *CloudWatchLogsSink) Handle(event ProgressEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return s.api.PutLogEvents(s.logGroupName, s.logStreamName, []CloudWatchLogEvent{
+		{Timestamp: time.Now(), Message: string(data)},
+	})
+}
+
+// Close is a no-op: the CloudWatch Logs API client is shared and owned by
+// the caller.
+func (s *CloudWatchLogsSink) Close() {}
+
+// awsCloudWatchLogsAPI adapts cloudwatchlogsiface.CloudWatchLogsAPI to
+// cloudWatchLogsAPI, so a real AWS SDK client can back a CloudWatchLogsSink.
+type awsCloudWatchLogsAPI struct {
+	client cloudwatchlogsiface.CloudWatchLogsAPI
+}
+
+// NewAWSCloudWatchLogsAPI returns a cloudWatchLogsAPI backed by client,
+// suitable for passing to NewCloudWatchLogsSink.
+func NewAWSCloudWatchLogsAPI(client cloudwatchlogsiface.CloudWatchLogsAPI) cloudWatchLogsAPI {
+	return &awsCloudWatchLogsAPI{client: client}
+}
+
+// PutLogEvents ensures logStreamName exists within logGroupName, tolerating
+// it already existing, then writes events to it.
+func (a *awsCloudWatchLogsAPI) PutLogEvents(logGroupName, logStreamName string, events []CloudWatchLogEvent) error {
+	_, err := a.client.CreateLogStream(&cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(logGroupName),
+		LogStreamName: aws.String(logStreamName),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != cloudwatchlogs.ErrCodeResourceAlreadyExistsException {
+			return err
+		}
+	}
+
+	inputEvents := make([]*cloudwatchlogs.InputLogEvent, len(events))
+	for i, event := range events {
+		inputEvents[i] = &cloudwatchlogs.InputLogEvent{
+			Timestamp: aws.Int64(event.Timestamp.UnixMilli()),
+			Message:   aws.String(event.Message),
+		}
+	}
+
+	_, err = a.client.PutLogEvents(&cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String(logGroupName),
+		LogStreamName: aws.String(logStreamName),
+		LogEvents:     inputEvents,
+	})
+	return err
+}
@@ -0,0 +1,51 @@
+package cdk
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCloudWatchLogsAPI struct {
+	logGroupName  string
+	logStreamName string
+	events        []CloudWatchLogEvent
+	err           error
+}
+
+func (f *fakeCloudWatchLogsAPI) PutLogEvents(logGroupName, logStreamName string, events []CloudWatchLogEvent) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.logGroupName = logGroupName
+	f.logStreamName = logStreamName
+	f.events = append(f.events, events...)
+	return nil
+}
+
+func Test_CloudWatchLogsSink_Handle_WritesJSONLogLine(t *testing.T) {
+	api := &fakeCloudWatchLogsAPI{}
+	sink := NewCloudWatchLogsSink(api, "/agc/progress", "myExecution")
+
+	require.NoError(t, sink.Handle(ProgressEvent{ExecutionName: "myExecution", CurrentStep: 1, TotalSteps: 2}))
+
+	assert.Equal(t, "/agc/progress", api.logGroupName)
+	assert.Equal(t, "myExecution", api.logStreamName)
+	require.Len(t, api.events, 1)
+
+	var event ProgressEvent
+	require.NoError(t, json.Unmarshal([]byte(api.events[0].Message), &event))
+	assert.Equal(t, "myExecution", event.ExecutionName)
+	assert.Equal(t, 1, event.CurrentStep)
+}
+
+func Test_CloudWatchLogsSink_Handle_ReturnsAPIError(t *testing.T) {
+	api := &fakeCloudWatchLogsAPI{err: errors.New("throttled")}
+	sink := NewCloudWatchLogsSink(api, "/agc/progress", "myExecution")
+
+	err := sink.Handle(ProgressEvent{ExecutionName: "myExecution"})
+	assert.EqualError(t, err, "throttled")
+}
@@ -0,0 +1,38 @@
+package cdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// FileSink appends every ProgressEvent it handles as a single line of JSON
+// to a local file, for offline postmortems of a cdk execution.
+type FileSink struct {
+	file *os.File
+}
+
+// NewFileSink opens (creating if necessary) path for appending and returns a
+// FileSink that writes events to it.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening progress sink file %q: %w", path, err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Handle appends event to the sink's file as a single line of JSON.
+func (s *FileSink) Handle(event ProgressEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = s.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the underlying file.
+func (s *FileSink) Close() {
+	_ = s.file.Close()
+}
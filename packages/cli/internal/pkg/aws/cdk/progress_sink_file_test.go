@@ -0,0 +1,40 @@
+package cdk
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FileSink_Handle_AppendsOneJSONLinePerEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.jsonl")
+
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Handle(ProgressEvent{ExecutionName: "myExecution", CurrentStep: 1, TotalSteps: 2}))
+	require.NoError(t, sink.Handle(ProgressEvent{ExecutionName: "myExecution", CurrentStep: 2, TotalSteps: 2}))
+	sink.Close()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, lines, 2)
+
+	var event ProgressEvent
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(t, "myExecution", event.ExecutionName)
+	assert.Equal(t, 2, event.CurrentStep)
+}
@@ -0,0 +1,59 @@
+package cdk
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	cdkDeployCurrentStep = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "agc_cdk_deploy_current_step",
+		Help: "Current step of the most recent cdk deploy/destroy progress event, by execution.",
+	}, []string{"execution_name"})
+
+	cdkDeployTotalSteps = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "agc_cdk_deploy_total_steps",
+		Help: "Total steps of the most recent cdk deploy/destroy progress event, by execution.",
+	}, []string{"execution_name"})
+
+	cdkDeployErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "agc_cdk_deploy_errors_total",
+		Help: "Total number of cdk deploy/destroy progress events carrying an error, by execution.",
+	}, []string{"execution_name"})
+)
+
+// PrometheusSink records every ProgressEvent it handles as
+// agc_cdk_deploy_current_step/agc_cdk_deploy_total_steps gauges and an
+// agc_cdk_deploy_errors_total counter, each labeled by execution name.
+type PrometheusSink struct{}
+
+// NewPrometheusSink registers this sink's metrics with registerer, tolerating
+// an AlreadyRegisteredError so repeated calls against the same registerer
+// are safe, and returns a PrometheusSink ready to handle events.
+func NewPrometheusSink(registerer prometheus.Registerer) (*PrometheusSink, error) {
+	for _, collector := range []prometheus.Collector{cdkDeployCurrentStep, cdkDeployTotalSteps, cdkDeployErrorsTotal} {
+		if err := registerer.Register(collector); err != nil {
+			if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+				return nil, err
+			}
+		}
+	}
+	return &PrometheusSink{}, nil
+}
+
+// Handle updates this sink's gauges and counter for event.ExecutionName.
+// The step gauges are only updated for events that actually carry step
+// data (TotalSteps > 0) so that step-less events, e.g. the log events this
+// package's SinkPipeline publishes on a sink failure, don't reset them back
+// to zero.
+func (s *PrometheusSink) Handle(event ProgressEvent) error {
+	labels := prometheus.Labels{"execution_name": event.ExecutionName}
+	if event.TotalSteps > 0 {
+		cdkDeployCurrentStep.With(labels).Set(float64(event.CurrentStep))
+		cdkDeployTotalSteps.With(labels).Set(float64(event.TotalSteps))
+	}
+	if event.Err != nil {
+		cdkDeployErrorsTotal.With(labels).Inc()
+	}
+	return nil
+}
+
+// Close is a no-op: the metrics remain registered for the process lifetime.
+func (s *PrometheusSink) Close() {}
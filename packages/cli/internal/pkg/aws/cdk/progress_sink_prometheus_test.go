@@ -0,0 +1,33 @@
+package cdk
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PrometheusSink_Handle_RecordsStepsAndErrors(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	sink, err := NewPrometheusSink(registry)
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Handle(ProgressEvent{ExecutionName: "prometheusSinkRecordsExecution", CurrentStep: 2, TotalSteps: 4}))
+	require.NoError(t, sink.Handle(ProgressEvent{ExecutionName: "prometheusSinkRecordsExecution", Err: errors.New("boom")}))
+
+	labels := prometheus.Labels{"execution_name": "prometheusSinkRecordsExecution"}
+	require.Equal(t, float64(2), testutil.ToFloat64(cdkDeployCurrentStep.With(labels)))
+	require.Equal(t, float64(4), testutil.ToFloat64(cdkDeployTotalSteps.With(labels)))
+	require.Equal(t, float64(1), testutil.ToFloat64(cdkDeployErrorsTotal.With(labels)))
+}
+
+func Test_NewPrometheusSink_ToleratesDoubleRegistration(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	_, err := NewPrometheusSink(registry)
+	require.NoError(t, err)
+
+	_, err = NewPrometheusSink(registry)
+	require.NoError(t, err)
+}
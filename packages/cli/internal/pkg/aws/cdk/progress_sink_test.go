@@ -0,0 +1,126 @@
+package cdk
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSink struct {
+	mu      sync.Mutex
+	handled []ProgressEvent
+	err     error
+	closed  bool
+}
+
+func (s *fakeSink) Handle(event ProgressEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handled = append(s.handled, event)
+	return s.err
+}
+
+func (s *fakeSink) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+func (s *fakeSink) snapshot() ([]ProgressEvent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ProgressEvent(nil), s.handled...), s.closed
+}
+
+func Test_SinkPipeline_Handle_FansOutToEverySink(t *testing.T) {
+	sinkA, sinkB := &fakeSink{}, &fakeSink{}
+	pipeline := NewSinkPipeline("myExecution", nil, sinkA, sinkB)
+
+	event := ProgressEvent{ExecutionName: "myExecution", CurrentStep: 1, TotalSteps: 2}
+	pipeline.Handle(event)
+	pipeline.Close()
+
+	for _, sink := range []*fakeSink{sinkA, sinkB} {
+		handled, closed := sink.snapshot()
+		assert.Equal(t, []ProgressEvent{event}, handled)
+		assert.True(t, closed)
+	}
+}
+
+func Test_SinkPipeline_Handle_SinkFailureIsReportedNotFatal(t *testing.T) {
+	failing := &fakeSink{err: errors.New("boom")}
+	broker := NewProgressBroker()
+	errEvents, cancel := broker.Subscribe("myExecution")
+	defer cancel()
+
+	pipeline := NewSinkPipeline("myExecution", broker, failing)
+	pipeline.Handle(ProgressEvent{ExecutionName: "myExecution"})
+	pipeline.Close()
+
+	select {
+	case event := <-errEvents:
+		assert.Equal(t, ProgressEventTypeLog, event.Type)
+		assert.Contains(t, event.Outputs[0], "boom")
+	case <-time.After(time.Second):
+		t.Fatal("expected a log event reporting the sink failure")
+	}
+}
+
+func Test_SinkPipeline_Handle_SinkFailureIsReportedAfterProgressHasAlreadyStarted(t *testing.T) {
+	failing := &fakeSink{err: errors.New("boom")}
+	broker := NewProgressBroker()
+	events, cancel := broker.Subscribe("myExecution")
+	defer cancel()
+
+	// A subscriber that has already seen real progress has a non-zero
+	// sub.last.Seq, so the coalescing filter would otherwise drop the
+	// zero-Seq log event a sink failure publishes; this is the realistic
+	// ordering a live deploy produces.
+	broker.Publish(ProgressEvent{ExecutionName: "myExecution", Seq: 1, CurrentStep: 1, TotalSteps: 2})
+	require.Equal(t, ProgressEvent{ExecutionName: "myExecution", Seq: 1, CurrentStep: 1, TotalSteps: 2}, <-events)
+
+	pipeline := NewSinkPipeline("myExecution", broker, failing)
+	pipeline.Handle(ProgressEvent{ExecutionName: "myExecution", Seq: 2, CurrentStep: 1, TotalSteps: 2})
+	pipeline.Close()
+
+	select {
+	case event := <-events:
+		assert.Equal(t, ProgressEventTypeLog, event.Type)
+		assert.Contains(t, event.Outputs[0], "boom")
+	case <-time.After(time.Second):
+		t.Fatal("expected a log event reporting the sink failure even though progress had already started")
+	}
+}
+
+// blockingSink never returns from Handle, simulating a sink that has
+// stalled (e.g. on a wedged network call), so tests can assert Handle still
+// doesn't block the caller.
+type blockingSink struct{}
+
+func (blockingSink) Handle(ProgressEvent) error {
+	select {}
+}
+
+func (blockingSink) Close() {}
+
+func Test_SinkPipeline_Handle_DoesNotBlockOnFullQueue(t *testing.T) {
+	pipeline := NewSinkPipeline("myExecution", nil, blockingSink{})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < sinkQueueSize*2; i++ {
+			pipeline.Handle(ProgressEvent{ExecutionName: "myExecution", CurrentStep: i})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Handle blocked instead of dropping events for a full sink queue")
+	}
+}
@@ -56,7 +56,7 @@ func Test_updateResultFromStream_Success(t *testing.T) {
 	testChannel := make(ProgressStream)
 	progressResult := Result{}
 
-	go updateResultFromStream(testChannel, &progressResult, &waitGroup)
+	go updateResultFromStream(testChannel, &progressResult, &waitGroup, nil, nil)
 
 	sentEvent := ProgressEvent{ExecutionName: "someKey", Outputs: []string{"hi"}}
 	testChannel <- sentEvent
@@ -64,12 +64,10 @@ func Test_updateResultFromStream_Success(t *testing.T) {
 
 	waitGroup.Wait()
 
-	expectedProgressResult := Result{
-		ExecutionName: "someKey",
-		Outputs:       []string{"hi"},
-	}
-
-	assert.Equal(t, expectedProgressResult, progressResult)
+	assert.Equal(t, "someKey", progressResult.ExecutionName)
+	assert.Equal(t, []string{"hi"}, progressResult.Outputs)
+	assert.Equal(t, uint64(1), progressResult.Seq)
+	assert.False(t, progressResult.Timestamp.IsZero())
 }
 
 func Test_updateResultFromStream_Error(t *testing.T) {
@@ -79,7 +77,7 @@ func Test_updateResultFromStream_Error(t *testing.T) {
 	testChannel := make(ProgressStream)
 	progressResult := Result{}
 
-	go updateResultFromStream(testChannel, &progressResult, &waitGroup)
+	go updateResultFromStream(testChannel, &progressResult, &waitGroup, nil, nil)
 
 	sentEvent := ProgressEvent{ExecutionName: "someKey", Outputs: []string{"hi"}}
 	testChannel <- sentEvent
@@ -94,109 +92,128 @@ func Test_updateResultFromStream_Error(t *testing.T) {
 
 	waitGroup.Wait()
 
-	expectedProgressResult := Result{
-		ExecutionName: "someKey",
-		Outputs:       []string{"hi"},
-		Err:           errors.New("some error"),
+	assert.Equal(t, "someKey", progressResult.ExecutionName)
+	assert.Equal(t, []string{"hi"}, progressResult.Outputs)
+	assert.Equal(t, errors.New("some error"), progressResult.Err)
+	assert.Equal(t, uint64(2), progressResult.Seq)
+}
+
+func Test_updateResultFromStream_ForwardsEventsToPipeline(t *testing.T) {
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(1)
+
+	testChannel := make(ProgressStream)
+	progressResult := Result{}
+
+	sink := &fakeSink{}
+	pipeline := NewSinkPipeline("someKey", nil, sink)
+
+	go updateResultFromStream(testChannel, &progressResult, &waitGroup, pipeline, nil)
+
+	sentEvent := ProgressEvent{ExecutionName: "someKey", CurrentStep: 1, TotalSteps: 2}
+	testChannel <- sentEvent
+	close(testChannel)
+
+	waitGroup.Wait()
+	pipeline.Close()
+
+	handled, closed := sink.snapshot()
+	assert.True(t, closed)
+	if assert.Len(t, handled, 1) {
+		assert.Equal(t, progressResult.Seq, handled[0].Seq)
+		assert.Equal(t, sentEvent.CurrentStep, handled[0].CurrentStep)
 	}
+}
+
+func Test_updateResultFromStream_PublishesToBrokerAndClosesOnStreamEnd(t *testing.T) {
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(1)
 
-	assert.Equal(t, expectedProgressResult, progressResult)
+	testChannel := make(ProgressStream)
+	progressResult := Result{}
+
+	broker := NewProgressBroker()
+	events, cancel := broker.Subscribe("someKey")
+	defer cancel()
+
+	go updateResultFromStream(testChannel, &progressResult, &waitGroup, nil, broker)
+
+	sentEvent := ProgressEvent{ExecutionName: "someKey", CurrentStep: 1, TotalSteps: 2}
+	testChannel <- sentEvent
+	close(testChannel)
+
+	published := <-events
+	assert.Equal(t, progressResult.Seq, published.Seq)
+	assert.Equal(t, sentEvent.CurrentStep, published.CurrentStep)
+
+	waitGroup.Wait()
+
+	_, open := <-events
+	assert.False(t, open, "broker.Close should have been called for the execution once the stream ended")
 }
 
-func Test_isProgressEvent(t *testing.T) {
+func Test_isForwardProgress(t *testing.T) {
 	tests := map[string]struct {
 		oldProgressEvent ProgressEvent
 		newProgressEvent ProgressEvent
 		expected         bool
 	}{
-		"empty old event": {
-			oldProgressEvent: ProgressEvent{
-				TotalSteps:  4,
-				CurrentStep: 2,
-			},
-			newProgressEvent: ProgressEvent{},
-			expected:         false,
+		"old event has no seq": {
+			oldProgressEvent: ProgressEvent{},
+			newProgressEvent: ProgressEvent{Seq: 1},
+			expected:         true,
 		},
-		"empty new event": {
-			oldProgressEvent: ProgressEvent{
-				TotalSteps:  4,
-				CurrentStep: 2,
-			},
+		"old and new event both have no seq": {
+			oldProgressEvent: ProgressEvent{},
 			newProgressEvent: ProgressEvent{},
 			expected:         false,
 		},
-		"current step is 0 for new event": {
-			oldProgressEvent: ProgressEvent{
-				TotalSteps:  4,
-				CurrentStep: 2,
-			},
-			newProgressEvent: ProgressEvent{
-				TotalSteps:  4,
-				CurrentStep: 0,
-			},
-			expected: false,
-		},
-		"old event step is 0": {
-			oldProgressEvent: ProgressEvent{
-				TotalSteps:  4,
-				CurrentStep: 0,
-			},
-			newProgressEvent: ProgressEvent{
-				TotalSteps:  4,
-				CurrentStep: 2,
-			},
-			expected: true,
-		},
-		"progress is not moving forward": {
-			oldProgressEvent: ProgressEvent{
-				TotalSteps:  4,
-				CurrentStep: 2,
-			},
-			newProgressEvent: ProgressEvent{
-				TotalSteps:  4,
-				CurrentStep: 1,
-			},
-			expected: false,
-		},
-		"progress has moved forward": {
-			oldProgressEvent: ProgressEvent{
-				TotalSteps:  4,
-				CurrentStep: 3,
-			},
-			newProgressEvent: ProgressEvent{
-				TotalSteps:  4,
-				CurrentStep: 4,
-			},
-			expected: true,
+		"new event seq is not greater": {
+			oldProgressEvent: ProgressEvent{Seq: 2},
+			newProgressEvent: ProgressEvent{Seq: 2},
+			expected:         false,
 		},
-		"event change with no improved progress": {
-			oldProgressEvent: ProgressEvent{
-				TotalSteps:  4,
-				CurrentStep: 2,
-			},
-			newProgressEvent: ProgressEvent{
-				TotalSteps:  9,
-				CurrentStep: 4,
-			},
-			expected: false,
+		"new event seq has moved backward": {
+			oldProgressEvent: ProgressEvent{Seq: 3},
+			newProgressEvent: ProgressEvent{Seq: 2},
+			expected:         false,
 		},
-		"event change with improved progress": {
-			oldProgressEvent: ProgressEvent{
-				TotalSteps:  4,
-				CurrentStep: 2,
-			},
-			newProgressEvent: ProgressEvent{
-				TotalSteps:  1,
-				CurrentStep: 1,
-			},
-			expected: true,
+		"new event seq has moved forward": {
+			oldProgressEvent: ProgressEvent{Seq: 3},
+			newProgressEvent: ProgressEvent{Seq: 4},
+			expected:         true,
 		},
 	}
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			actual := doesProgressEventBar(tt.newProgressEvent, tt.oldProgressEvent)
+			actual := isForwardProgress(tt.newProgressEvent, tt.oldProgressEvent)
 			assert.Equal(t, tt.expected, actual)
 		})
 	}
 }
+
+func Test_ProgressEvent_MarshalUnmarshalJSON_RoundTrips(t *testing.T) {
+	sent := ProgressEvent{
+		ExecutionName: "someKey",
+		Type:          ProgressEventTypeError,
+		Seq:           5,
+		CurrentStep:   2,
+		TotalSteps:    4,
+		Outputs:       []string{"hi"},
+		Err:           errors.New("some error"),
+	}
+
+	data, err := sent.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"schemaVersion":1`)
+
+	var got ProgressEvent
+	assert.NoError(t, got.UnmarshalJSON(data))
+
+	assert.Equal(t, sent.ExecutionName, got.ExecutionName)
+	assert.Equal(t, sent.Type, got.Type)
+	assert.Equal(t, sent.Seq, got.Seq)
+	assert.Equal(t, sent.Outputs, got.Outputs)
+	assert.Equal(t, sent.Err.Error(), got.Err.Error())
+}